@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/mkman/spiff"
+)
+
+const (
+	doctorTemplate       = "doctor: (( merge ))\n"
+	doctorStub           = "doctor: ok\n"
+	doctorExpectedOutput = "doctor: ok"
+)
+
+type DoctorCommand struct {
+}
+
+func (command *DoctorCommand) Execute(args []string) error {
+	s, err := spiff.NewSpiff(Mkman.Tracer())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("spiff path: %s\n", s.Path())
+
+	version, err := s.Version()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("spiff version: %s\n", version)
+
+	err = checkMerge(s)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("spiff merge: ok\n")
+
+	return nil
+}
+
+func checkMerge(s *spiff.Spiff) error {
+	tempDir, err := ioutil.TempDir("", "mkman-doctor")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	templatePath := filepath.Join(tempDir, "template.yml")
+	err = ioutil.WriteFile(templatePath, []byte(doctorTemplate), 0644)
+	if err != nil {
+		return err
+	}
+
+	stubPath := filepath.Join(tempDir, "stub.yml")
+	err = ioutil.WriteFile(stubPath, []byte(doctorStub), 0644)
+	if err != nil {
+		return err
+	}
+
+	output, err := s.Merge(templatePath, stubPath)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(output)) != doctorExpectedOutput {
+		return fmt.Errorf("spiff merge returned unexpected output: %q", output)
+	}
+
+	return nil
+}
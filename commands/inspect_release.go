@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf-experimental/mkman/release"
+)
+
+type InspectReleaseCommand struct {
+	Args struct {
+		ReleasePath string `positional-arg-name:"release-path" description:"Path to a release tarball or extracted release directory"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (command *InspectReleaseCommand) Execute(args []string) error {
+	metadata, err := release.ReadMetadata(command.Args.ReleasePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("name: %s\n", metadata.Name)
+	fmt.Printf("version: %s\n", metadata.Version)
+	fmt.Printf("jobs:\n")
+	for _, job := range metadata.Jobs {
+		fmt.Printf("- %s\n", job.Name)
+	}
+
+	return nil
+}
@@ -1,10 +1,29 @@
 package commands
 
+import (
+	"os"
+
+	"github.com/pivotal-cf-experimental/mkman/tracer"
+)
+
 type MkmanCommand struct {
-	Version   func()           `long:"version" description:"Print version"`
-	PrintAmit PrintAmitCommand `command:"print-amit" description:"Prints the man behind 'mkman'"`
+	Version        func()                `long:"version" description:"Print version"`
+	Trace          bool                  `long:"trace" description:"Log every external command to stderr before running it"`
+	TraceRedact    bool                  `long:"trace-redact" description:"Mask secret-looking values in --trace output"`
+	PrintAmit      PrintAmitCommand      `command:"print-amit" description:"Prints the man behind 'mkman'"`
+	Doctor         DoctorCommand         `command:"doctor" description:"Checks that spiff is installed and can merge stubs"`
+	InspectRelease InspectReleaseCommand `command:"inspect-release" description:"Prints the name, version and jobs of a release"`
+	Preflight      PreflightCommand      `command:"preflight" description:"Checks that all external dependencies are available"`
 }
 
 var Mkman MkmanCommand = MkmanCommand{
 	Version: VersionFunc,
 }
+
+func (command MkmanCommand) Tracer() *tracer.Tracer {
+	if !command.Trace {
+		return nil
+	}
+
+	return tracer.NewTracer(os.Stderr, command.TraceRedact)
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf-experimental/mkman/spiff"
+)
+
+type preflightCheck struct {
+	name string
+	run  func() error
+}
+
+type PreflightCommand struct {
+}
+
+func (command *PreflightCommand) Execute(args []string) error {
+	var s *spiff.Spiff
+
+	checks := []preflightCheck{
+		{
+			name: "spiff in PATH",
+			run: func() error {
+				var err error
+				s, err = spiff.NewSpiff(Mkman.Tracer())
+				return err
+			},
+		},
+		{
+			name: "spiff merge",
+			run: func() error {
+				if s == nil {
+					return errors.New("spiff not available")
+				}
+				return checkMerge(s)
+			},
+		},
+		{
+			name: "temp directory writable",
+			run:  checkTempDirWritable,
+		},
+	}
+
+	failures := 0
+	for _, check := range checks {
+		err := check.run()
+		if err != nil {
+			failures++
+			fmt.Printf("[FAIL] %s: %s\n", check.name, err)
+			continue
+		}
+		fmt.Printf("[PASS] %s\n", check.name)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d preflight checks failed", failures, len(checks))
+	}
+
+	return nil
+}
+
+func checkTempDirWritable() error {
+	file, err := ioutil.TempFile("", "mkman-preflight")
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	return os.Remove(file.Name())
+}
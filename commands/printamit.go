@@ -46,6 +46,6 @@ func (command *PrintAmitCommand) Execute(args []string) error {
 		"'++++'',`                       `#++;:,,..........,,,,,..,,,::;;+                              :++++\n" +
 		"                                  ,+';;:,,...............,,:::'                                     \n" +
 		"                                      '::,...````..`````..,,,                                       \n"
-	fmt.Printf(amit)
+	fmt.Print(amit)
 	return nil
 }
@@ -1,7 +1,12 @@
 package main_test
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -12,8 +17,61 @@ import (
 
 const (
 	executableTimeout = 5 * time.Second
+
+	workingSpiffScript = `#!/bin/sh
+case "$1" in
+  --version) echo "spiff version 1.0.7" ;;
+  merge) echo "doctor: ok" ;;
+esac
+`
+
+	etcdReleaseManifest = `---
+name: etcd
+version: 210
+jobs:
+- name: etcd
+  version: 3b4f2c1
+- name: etcd_metrics_server
+  version: 9e2a7d0
+`
+
+	failingSpiffScript = `#!/bin/sh
+case "$1" in
+  --version) echo "spiff version 1.0.7" ;;
+  merge) echo "merge exploded" >&2; exit 1 ;;
+esac
+`
 )
 
+func writeTarball(tarballPath string, files map[string]string) {
+	file, err := os.Create(tarballPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	defer gzipWriter.Close()
+
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	for name, contents := range files {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(contents)),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tarWriter.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+func writeFakeSpiff(dir string, script string) {
+	err := ioutil.WriteFile(filepath.Join(dir, "spiff"), []byte(script), 0755)
+	Expect(err).NotTo(HaveOccurred())
+}
+
 var _ = Describe("Executing binary", func() {
 	var (
 		args []string
@@ -80,4 +138,271 @@ var _ = Describe("Executing binary", func() {
 			Expect(session.Err).To(gbytes.Say("Unknown command"))
 		})
 	})
+
+	Context("when doctor is provided", func() {
+		var (
+			spiffDir string
+		)
+
+		BeforeEach(func() {
+			args = []string{"doctor"}
+
+			var err error
+			spiffDir, err = ioutil.TempDir("", "mkman-test-spiff")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(spiffDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when spiff is working", func() {
+			BeforeEach(func() {
+				writeFakeSpiff(spiffDir, workingSpiffScript)
+			})
+
+			It("reports the spiff path, version and a successful merge", func() {
+				command := exec.Command(binPath, args...)
+				command.Env = []string{"PATH=" + spiffDir}
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say("spiff path: %s", filepath.Join(spiffDir, "spiff")))
+				Expect(session.Out).To(gbytes.Say("spiff version: spiff version 1.0.7"))
+				Expect(session.Out).To(gbytes.Say("spiff merge: ok"))
+			})
+		})
+
+		Context("when --trace is provided", func() {
+			BeforeEach(func() {
+				args = []string{"--trace", "doctor"}
+				writeFakeSpiff(spiffDir, workingSpiffScript)
+			})
+
+			It("logs each spiff invocation to stderr", func() {
+				command := exec.Command(binPath, args...)
+				command.Env = []string{"PATH=" + spiffDir}
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(0))
+				Expect(session.Err).To(gbytes.Say(`\+ \[.*\] .*spiff --version`))
+				Expect(session.Err).To(gbytes.Say(`\+ \[.*\] .*spiff merge .*template.yml .*stub.yml`))
+			})
+		})
+
+		Context("when spiff is not in the PATH", func() {
+			It("exits with error", func() {
+				command := exec.Command(binPath, args...)
+				command.Env = []string{"PATH=" + spiffDir}
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("spiff not found in PATH"))
+			})
+		})
+
+		Context("when spiff fails to merge", func() {
+			BeforeEach(func() {
+				writeFakeSpiff(spiffDir, failingSpiffScript)
+			})
+
+			It("exits with error", func() {
+				command := exec.Command(binPath, args...)
+				command.Env = []string{"PATH=" + spiffDir}
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("spiff merge failed"))
+				Expect(session.Err).To(gbytes.Say("merge exploded"))
+			})
+		})
+	})
+
+	Context("when inspect-release is provided", func() {
+		var (
+			releaseDir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			releaseDir, err = ioutil.TempDir("", "mkman-test-release")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(releaseDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		itPrintsTheReleaseMetadata := func() {
+			It("prints the release name, version and jobs", func() {
+				command := exec.Command(binPath, args...)
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say("name: etcd"))
+				Expect(session.Out).To(gbytes.Say("version: 210"))
+				Expect(session.Out).To(gbytes.Say("jobs:"))
+				Expect(session.Out).To(gbytes.Say("- etcd"))
+				Expect(session.Out).To(gbytes.Say("- etcd_metrics_server"))
+			})
+		}
+
+		Context("when the release is a tarball", func() {
+			BeforeEach(func() {
+				tarballPath := filepath.Join(releaseDir, "etcd-release.tgz")
+				writeTarball(tarballPath, map[string]string{
+					"./release.MF": etcdReleaseManifest,
+				})
+				args = []string{"inspect-release", tarballPath}
+			})
+
+			itPrintsTheReleaseMetadata()
+		})
+
+		Context("when the release is a directory", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(releaseDir, "release.MF"), []byte(etcdReleaseManifest), 0644)
+				Expect(err).NotTo(HaveOccurred())
+				args = []string{"inspect-release", releaseDir}
+			})
+
+			itPrintsTheReleaseMetadata()
+		})
+
+		Context("when the directory does not contain a release.MF", func() {
+			BeforeEach(func() {
+				args = []string{"inspect-release", releaseDir}
+			})
+
+			It("exits with error", func() {
+				command := exec.Command(binPath, args...)
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("no release.MF found in release directory"))
+			})
+		})
+
+		Context("when the tarball does not contain a release.MF", func() {
+			BeforeEach(func() {
+				tarballPath := filepath.Join(releaseDir, "stemcell.tgz")
+				writeTarball(tarballPath, map[string]string{
+					"./stemcell.MF": "name: some-stemcell",
+				})
+				args = []string{"inspect-release", tarballPath}
+			})
+
+			It("exits with error", func() {
+				command := exec.Command(binPath, args...)
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("file release.MF not found in tarball"))
+			})
+		})
+
+		Context("when the release path is not provided", func() {
+			BeforeEach(func() {
+				args = []string{"inspect-release"}
+			})
+
+			It("exits with error", func() {
+				command := exec.Command(binPath, args...)
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("release-path"))
+			})
+		})
+	})
+
+	Context("when preflight is provided", func() {
+		var (
+			spiffDir string
+			tempDir  string
+		)
+
+		BeforeEach(func() {
+			args = []string{"preflight"}
+
+			var err error
+			spiffDir, err = ioutil.TempDir("", "mkman-test-spiff")
+			Expect(err).NotTo(HaveOccurred())
+
+			tempDir = os.TempDir()
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(spiffDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		runPreflight := func() *gexec.Session {
+			command := exec.Command(binPath, args...)
+			command.Env = []string{"PATH=" + spiffDir, "TMPDIR=" + tempDir}
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			return session
+		}
+
+		Context("when all checks pass", func() {
+			BeforeEach(func() {
+				writeFakeSpiff(spiffDir, workingSpiffScript)
+			})
+
+			It("reports every check as passing", func() {
+				session := runPreflight()
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say(`\[PASS\] spiff in PATH`))
+				Expect(session.Out).To(gbytes.Say(`\[PASS\] spiff merge`))
+				Expect(session.Out).To(gbytes.Say(`\[PASS\] temp directory writable`))
+			})
+		})
+
+		Context("when spiff is not in the PATH", func() {
+			It("reports the spiff checks as failing and exits with error", func() {
+				session := runPreflight()
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Out).To(gbytes.Say(`\[FAIL\] spiff in PATH: spiff not found in PATH`))
+				Expect(session.Out).To(gbytes.Say(`\[FAIL\] spiff merge: spiff not available`))
+				Expect(session.Out).To(gbytes.Say(`\[PASS\] temp directory writable`))
+				Expect(session.Err).To(gbytes.Say("error"))
+				Expect(session.Err).To(gbytes.Say("2 of 3 preflight checks failed"))
+			})
+		})
+
+		Context("when the temp directory is not writable", func() {
+			BeforeEach(func() {
+				writeFakeSpiff(spiffDir, workingSpiffScript)
+				tempDir = filepath.Join(spiffDir, "does-not-exist")
+			})
+
+			It("reports the temp directory check as failing and exits with error", func() {
+				session := runPreflight()
+
+				Eventually(session, executableTimeout).Should(gexec.Exit(1))
+				Expect(session.Out).To(gbytes.Say(`\[PASS\] spiff in PATH`))
+				Expect(session.Out).To(gbytes.Say(`\[FAIL\] spiff merge`))
+				Expect(session.Out).To(gbytes.Say(`\[FAIL\] temp directory writable`))
+				Expect(session.Err).To(gbytes.Say("2 of 3 preflight checks failed"))
+			})
+		})
+	})
 })
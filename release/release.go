@@ -0,0 +1,58 @@
+package release
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf-experimental/mkman/tarball"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ManifestFilename = "release.MF"
+)
+
+type Job struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+type Metadata struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Jobs    []Job  `yaml:"jobs"`
+}
+
+func ReadMetadata(releasePath string) (Metadata, error) {
+	contents, err := readManifest(releasePath)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var metadata Metadata
+	err = yaml.Unmarshal(contents, &metadata)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	return metadata, nil
+}
+
+func readManifest(releasePath string) ([]byte, error) {
+	info, err := os.Stat(releasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		contents, err := ioutil.ReadFile(filepath.Join(releasePath, ManifestFilename))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s found in release directory %s", ManifestFilename, releasePath)
+		}
+		return contents, err
+	}
+
+	return tarball.NewTarballReader(releasePath).ReadFile(ManifestFilename)
+}
@@ -0,0 +1,73 @@
+package spiff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/mkman/tracer"
+)
+
+const (
+	BinaryName = "spiff"
+)
+
+type Spiff struct {
+	path   string
+	tracer *tracer.Tracer
+}
+
+func NewSpiff(t *tracer.Tracer) (*Spiff, error) {
+	path, err := exec.LookPath(BinaryName)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in PATH", BinaryName)
+	}
+
+	return &Spiff{
+		path:   path,
+		tracer: t,
+	}, nil
+}
+
+func (s *Spiff) Path() string {
+	return s.path
+}
+
+func (s *Spiff) Version() (string, error) {
+	output, err := s.run("--version")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (s *Spiff) Merge(templatePath string, stubPaths ...string) ([]byte, error) {
+	args := append([]string{"merge", templatePath}, stubPaths...)
+	return s.run(args...)
+}
+
+func (s *Spiff) run(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := exec.Command(s.path, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if s.tracer != nil {
+		s.tracer.TraceCommand(command)
+	}
+
+	err := command.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %s: %s",
+			BinaryName,
+			args[0],
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package tarball
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+)
+
+type Stats struct {
+	BytesRead int64
+	Duration  time.Duration
+}
+
+type TarballReader struct {
+	path  string
+	stats Stats
+}
+
+func NewTarballReader(path string) *TarballReader {
+	return &TarballReader{
+		path: path,
+	}
+}
+
+func (r *TarballReader) Stats() Stats {
+	return r.stats
+}
+
+func (r *TarballReader) ReadFile(name string) ([]byte, error) {
+	start := time.Now()
+
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	counter := &countingReader{reader: file}
+	defer func() {
+		r.stats.BytesRead += counter.count
+		r.stats.Duration += time.Since(start)
+	}()
+
+	gzipReader, err := gzip.NewReader(counter)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tarball %s: %s", r.path, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading tarball %s: %s", r.path, err)
+		}
+
+		if path.Clean(header.Name) == path.Clean(name) {
+			return ioutil.ReadAll(tarReader)
+		}
+	}
+
+	return nil, fmt.Errorf("file %s not found in tarball %s", name, r.path)
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
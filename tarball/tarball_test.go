@@ -0,0 +1,167 @@
+package tarball_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf-experimental/mkman/tarball"
+)
+
+type tarballEntry struct {
+	name     string
+	contents []byte
+}
+
+func writeTarball(tarballPath string, entries []tarballEntry) {
+	file, err := os.Create(tarballPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	defer gzipWriter.Close()
+
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	for _, entry := range entries {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name: entry.name,
+			Mode: 0644,
+			Size: int64(len(entry.contents)),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tarWriter.Write(entry.contents)
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+var _ = Describe("TarballReader", func() {
+	var (
+		tempDir     string
+		tarballPath string
+		reader      *tarball.TarballReader
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "mkman-tarball-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		tarballPath = filepath.Join(tempDir, "some.tgz")
+		writeTarball(tarballPath, []tarballEntry{
+			{name: "./stemcell.MF", contents: []byte("name: some-stemcell")},
+			{name: "./image", contents: []byte("some-image")},
+		})
+
+		reader = tarball.NewTarballReader(tarballPath)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("ReadFile", func() {
+		It("returns the contents of the requested file", func() {
+			contents, err := reader.ReadFile("stemcell.MF")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("name: some-stemcell"))
+		})
+
+		Context("when the file is not in the tarball", func() {
+			It("returns an error", func() {
+				_, err := reader.ReadFile("release.MF")
+				Expect(err).To(MatchError("file release.MF not found in tarball " + tarballPath))
+			})
+		})
+
+		Context("when the path is not a gzipped tarball", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(tarballPath, []byte("not a tarball"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				_, err := reader.ReadFile("stemcell.MF")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when the file is followed by a large entry", func() {
+			BeforeEach(func() {
+				image := make([]byte, 1024*1024)
+				_, err := rand.Read(image)
+				Expect(err).NotTo(HaveOccurred())
+
+				writeTarball(tarballPath, []tarballEntry{
+					{name: "./stemcell.MF", contents: []byte("name: some-stemcell")},
+					{name: "./image", contents: image},
+				})
+
+				info, err := os.Stat(tarballPath)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Cut the archive off part way through the image so that
+				// any attempt to read past stemcell.MF fails.
+				err = os.Truncate(tarballPath, info.Size()/2)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("stops reading once the file is found", func() {
+				contents, err := reader.ReadFile("stemcell.MF")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("name: some-stemcell"))
+
+				info, err := os.Stat(tarballPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reader.Stats().BytesRead).To(BeNumerically("<", info.Size()))
+			})
+
+			It("fails for files after the truncation point", func() {
+				_, err := reader.ReadFile("missing")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).NotTo(ContainSubstring("not found"))
+			})
+		})
+	})
+
+	Describe("Stats", func() {
+		It("starts empty", func() {
+			Expect(reader.Stats()).To(Equal(tarball.Stats{}))
+		})
+
+		Context("when the whole tarball is read", func() {
+			It("records the size of the tarball as bytes read", func() {
+				_, err := reader.ReadFile("missing")
+				Expect(err).To(HaveOccurred())
+
+				info, err := os.Stat(tarballPath)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(reader.Stats().BytesRead).To(Equal(info.Size()))
+				Expect(reader.Stats().Duration).To(BeNumerically(">", 0))
+			})
+		})
+
+		Context("when the tarball is read more than once", func() {
+			It("accumulates the stats", func() {
+				_, err := reader.ReadFile("missing")
+				Expect(err).To(HaveOccurred())
+				first := reader.Stats()
+
+				_, err = reader.ReadFile("missing")
+				Expect(err).To(HaveOccurred())
+
+				Expect(reader.Stats().BytesRead).To(Equal(2 * first.BytesRead))
+				Expect(reader.Stats().Duration).To(BeNumerically(">", first.Duration))
+			})
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package tracer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	redactedValue = "<redacted>"
+)
+
+var secretPattern = regexp.MustCompile(`(?i)^([^=]*(password|secret|token|key)[^=]*=).+$`)
+
+type Tracer struct {
+	writer io.Writer
+	redact bool
+}
+
+func NewTracer(writer io.Writer, redact bool) *Tracer {
+	return &Tracer{
+		writer: writer,
+		redact: redact,
+	}
+}
+
+func (t *Tracer) TraceCommand(command *exec.Cmd) {
+	dir := command.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+
+	fmt.Fprintf(t.writer, "+ [%s] %s\n", dir, t.formatArgs(command.Args))
+}
+
+func (t *Tracer) formatArgs(args []string) string {
+	formatted := make([]string, len(args))
+
+	for i, arg := range args {
+		if t.redact {
+			arg = secretPattern.ReplaceAllString(arg, "${1}"+redactedValue)
+		}
+
+		if strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+
+		formatted[i] = arg
+	}
+
+	return strings.Join(formatted, " ")
+}
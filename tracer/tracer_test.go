@@ -0,0 +1,53 @@
+package tracer_test
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf-experimental/mkman/tracer"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		buffer  *gbytes.Buffer
+		command *exec.Cmd
+		redact  bool
+		t       *tracer.Tracer
+	)
+
+	BeforeEach(func() {
+		buffer = gbytes.NewBuffer()
+		command = exec.Command("spiff", "merge", "my template.yml", "--db-password=hunter2")
+		command.Dir = "/some/dir"
+		redact = false
+	})
+
+	JustBeforeEach(func() {
+		t = tracer.NewTracer(buffer, redact)
+	})
+
+	It("writes the working directory and full command line", func() {
+		t.TraceCommand(command)
+
+		Expect(buffer).To(gbytes.Say(
+			`\+ \[/some/dir\] spiff merge "my template.yml" --db-password=hunter2`,
+		))
+	})
+
+	Context("when redact is enabled", func() {
+		BeforeEach(func() {
+			redact = true
+		})
+
+		It("masks values that look like secrets", func() {
+			t.TraceCommand(command)
+
+			Expect(buffer).To(gbytes.Say(
+				`\+ \[/some/dir\] spiff merge "my template.yml" --db-password=<redacted>`,
+			))
+			Expect(buffer.Contents()).NotTo(ContainSubstring("hunter2"))
+		})
+	})
+})